@@ -0,0 +1,69 @@
+package errored
+
+// Code is a caller-defined classification for an *Error. On its own it
+// is just a number prefixed to the error string, but once registered
+// via RegisterCode it carries enough metadata for the erroredhttp and
+// erroredgrpc subpackages to map it onto the status representation of
+// those API boundaries.
+type Code int
+
+// CodeInfo is the metadata registered for a Code via RegisterCode.
+type CodeInfo struct {
+	Name        string
+	Description string
+	HTTPStatus  int
+
+	// GRPCCode is the numeric value of the corresponding
+	// google.golang.org/grpc/codes.Code. It is stored as a plain int so
+	// that the base errored package does not import the grpc package;
+	// erroredgrpc converts it back with codes.Code(info.GRPCCode).
+	GRPCCode int
+}
+
+var codeRegistry = map[Code]CodeInfo{}
+
+// RegisterCode records name, description, and the corresponding HTTP
+// status and gRPC code for code. Later calls for the same code replace
+// the previous registration.
+func RegisterCode(code Code, name, description string, httpStatus, grpcCode int) {
+	codeRegistry[code] = CodeInfo{
+		Name:        name,
+		Description: description,
+		HTTPStatus:  httpStatus,
+		GRPCCode:    grpcCode,
+	}
+}
+
+// LookupCode returns the metadata registered for code via RegisterCode,
+// and whether anything was registered for it.
+func LookupCode(code Code) (CodeInfo, bool) {
+	info, ok := codeRegistry[code]
+	return info, ok
+}
+
+// CodeOf walks err's Unwrap chain, including the constituents of any
+// *Error combined via Combine, and returns the first non-zero Code it
+// finds.
+func CodeOf(err error) (Code, bool) {
+	for err != nil {
+		if e, ok := err.(*Error); ok && e.Code != 0 {
+			return e.Code, true
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				if code, ok := CodeOf(inner); ok {
+					return code, true
+				}
+			}
+			return 0, false
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return 0, false
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,291 @@
+// Package errored provides an easy way to produce richer errors in
+// golang without having to think too hard about it. Errors created with
+// New or Errorf carry the stack trace of where they were created, and
+// can be combined together into a single error that still knows about
+// the state of the error(s) it is comprised of.
+package errored
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// AlwaysDebug turns on debug output (the source location of the error)
+// for every *Error, regardless of the per-error SetDebug setting.
+var AlwaysDebug = false
+
+// AlwaysTrace turns on full stack trace output for every *Error,
+// regardless of the per-error SetTrace setting.
+var AlwaysTrace = false
+
+// Error wraps the error interface to provide a description, an optional
+// numeric Code, and the stack trace of where it (or, for a combined
+// error, each of its constituents) was created.
+type Error struct {
+	// Code is an arbitrary, caller-defined numeric classification for
+	// this error. It is prefixed to the error string when non-zero, and
+	// may optionally be registered via RegisterCode with metadata used
+	// by the erroredhttp and erroredgrpc subpackages.
+	Code Code
+
+	debug bool
+	trace bool
+
+	desc   string
+	stack  []*Frame
+	errors []error
+}
+
+// makeStack captures the stack of the calling goroutine, skipping skip
+// frames (in the same sense as runtime.Callers) above its own caller,
+// using the active Stacker (see SetStacker). The +1 accounts for
+// makeStack's own frame, which the Stacker has no way to know about.
+//
+// It, and every function that calls it directly (New, Errorf, Wrap,
+// WithMessage, topFrame),
+// is marked noinline so that FramePointerStacker, which walks the saved
+// frame-pointer chain directly instead of going through runtime.Callers,
+// always sees one real frame per call: an inlined frame has none of its
+// own to walk through, silently collapsing out of the chain and
+// throwing off every skip count that assumes one frame per call.
+//
+//go:noinline
+func makeStack(skip int) []*Frame {
+	return currentStacker.Capture(skip + 1)
+}
+
+// New creates a new *Error with the given description, capturing the
+// stack at the point of the call.
+//
+//go:noinline
+func New(desc string) *Error {
+	return &Error{
+		desc:  desc,
+		stack: makeStack(3),
+	}
+}
+
+// Errorf creates a new *Error with a description formatted per
+// fmt.Sprintf, capturing the stack at the point of the call.
+//
+//go:noinline
+func Errorf(format string, args ...interface{}) *Error {
+	return &Error{
+		desc:  fmt.Sprintf(format, args...),
+		stack: makeStack(3),
+	}
+}
+
+// Wrap creates a new *Error with the given message whose cause is err,
+// mirroring the github.com/pkg/errors Wrap API. The returned error's
+// Unwrap chain includes err, and its stack is captured at Wrap's own
+// call site rather than going through New, which would instead capture
+// the call site inside Wrap.
+//
+//go:noinline
+func Wrap(err error, msg string) *Error {
+	e := &Error{desc: msg, stack: makeStack(3)}
+	return e.Combine(err)
+}
+
+// WithMessage is an alias for Wrap, provided for parity with the
+// github.com/pkg/errors API. It is not implemented in terms of Wrap so
+// that its captured stack is its own call site, not Wrap's.
+//
+//go:noinline
+func WithMessage(err error, msg string) *Error {
+	e := &Error{desc: msg, stack: makeStack(3)}
+	return e.Combine(err)
+}
+
+// WithCode sets e.Code and returns e for chaining, e.g.
+// Errorf("not found").WithCode(CodeNotFound).
+func (e *Error) WithCode(code Code) *Error {
+	e.Code = code
+	return e
+}
+
+// SetDebug toggles whether Error() includes the source location this
+// error was created at. It returns e for chaining.
+func (e *Error) SetDebug(debug bool) *Error {
+	e.debug = debug
+	return e
+}
+
+// SetTrace toggles whether Error() includes the full stack trace of
+// this error. It returns e for chaining.
+func (e *Error) SetTrace(trace bool) *Error {
+	e.trace = trace
+	return e
+}
+
+// topFrame returns the frame the given error was created at, if known.
+// For a *Error this is its own top-of-stack frame; for any other error
+// it is the call site of Combine itself.
+//
+//go:noinline
+func topFrame(err error) *Frame {
+	if e, ok := err.(*Error); ok && len(e.stack) > 0 {
+		return e.stack[0]
+	}
+
+	if s := makeStack(4); len(s) > 0 {
+		return s[0]
+	}
+
+	return &Frame{}
+}
+
+func descOf(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.desc
+	}
+	return err.Error()
+}
+
+// Combine merges err into e, returning a new *Error whose description
+// joins both and whose constituents can be inspected with Contains,
+// ContainsFunc, or Unwrap. e itself is left unmodified. Combining a nil
+// error is a no-op that returns e.
+func (e *Error) Combine(err error) *Error {
+	if err == nil {
+		return e
+	}
+
+	newErr := &Error{
+		Code:  e.Code,
+		debug: e.debug,
+		trace: e.trace,
+		desc:  e.desc + ": " + descOf(err),
+	}
+
+	if len(e.errors) == 0 {
+		newErr.errors = []error{e, err}
+		newErr.stack = []*Frame{topFrame(e), topFrame(err)}
+	} else {
+		newErr.errors = append(append([]error{}, e.errors...), err)
+		newErr.stack = append(append([]*Frame{}, e.stack...), topFrame(err))
+	}
+
+	return newErr
+}
+
+// Unwrap returns the errors combined into e via Combine, in the order
+// they were combined, so that e participates in errors.Is/As trees the
+// same way errors.Join-ed errors do. It returns nil when e has not been
+// combined with anything.
+func (e *Error) Unwrap() []error {
+	return e.errors
+}
+
+// Is reports whether target is one of the errors combined into e (or e
+// itself, when e has not been combined with anything), matching by
+// identity. This makes the usual errors.Is idiom of checking against a
+// package-level sentinel (var ErrNotFound = errored.New(...)) work, but
+// does not match unrelated *Error values that merely share a Code and
+// description.
+func (e *Error) Is(target error) bool {
+	return e.ContainsFunc(func(err error) bool {
+		return err == target
+	})
+}
+
+// As finds the first error combined into e (or e itself, when e has not
+// been combined with anything) that is assignable to target, and if one
+// is found, assigns it to target and returns true.
+func (e *Error) As(target interface{}) bool {
+	val := reflect.ValueOf(target)
+	if val.Kind() != reflect.Ptr || val.IsNil() {
+		return false
+	}
+	elem := val.Elem()
+
+	found := false
+	e.ContainsFunc(func(err error) bool {
+		errVal := reflect.ValueOf(err)
+		if !errVal.IsValid() || !errVal.Type().AssignableTo(elem.Type()) {
+			return false
+		}
+		elem.Set(errVal)
+		found = true
+		return true
+	})
+
+	return found
+}
+
+// Contains reports whether target is one of the errors combined into e,
+// or e itself when e has not been combined with anything.
+func (e *Error) Contains(target error) bool {
+	return e.ContainsFunc(func(err error) bool {
+		return err == target
+	})
+}
+
+// ContainsFunc reports whether f returns true for any of the errors
+// combined into e, or for e itself when e has not been combined with
+// anything.
+func (e *Error) ContainsFunc(f func(error) bool) bool {
+	list := e.errors
+	if len(list) == 0 {
+		list = []error{e}
+	}
+
+	for _, err := range list {
+		if f(err) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Error renders the error. With no debug or trace flags set (per-error
+// or via AlwaysDebug/AlwaysTrace), it is just the description, prefixed
+// with Code when non-zero. With debug set, the source location of the
+// error is appended. With trace set, the full stack (one frame per
+// line) is appended instead, taking precedence over debug.
+func (e *Error) Error() string {
+	msg := e.message()
+
+	trace := e.trace || AlwaysTrace
+	debug := e.debug || AlwaysDebug
+
+	if trace {
+		return msg + "\n" + e.traceString()
+	}
+
+	if debug && len(e.stack) > 0 {
+		return fmt.Sprintf("%s [%s]", msg, e.stack[0].String())
+	}
+
+	return msg
+}
+
+// message is the concise description of e: its desc, prefixed with Code
+// when non-zero. It is what %s, %v, and %q render via Format, and what
+// Error() renders when neither debug nor trace is in effect.
+func (e *Error) message() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%d %s", e.Code, e.desc)
+	}
+	return e.desc
+}
+
+// Frames returns the stack frames captured for e: its own, if e has not
+// been combined with anything, or one top-of-stack frame per
+// constituent otherwise. This lets callers inspect the stack without
+// parsing Error()'s string output.
+func (e *Error) Frames() []*Frame {
+	return e.stack
+}
+
+func (e *Error) traceString() string {
+	var sb strings.Builder
+	for _, f := range e.stack {
+		sb.WriteString(f.String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
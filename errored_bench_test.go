@@ -0,0 +1,44 @@
+package errored
+
+import "testing"
+
+func BenchmarkNewEagerSymbolize(b *testing.B) {
+	SetLazySymbolize(false)
+
+	for i := 0; i < b.N; i++ {
+		_ = New("benchmark error")
+	}
+}
+
+func BenchmarkNewLazySymbolize(b *testing.B) {
+	SetLazySymbolize(true)
+	defer SetLazySymbolize(false)
+
+	for i := 0; i < b.N; i++ {
+		_ = New("benchmark error")
+	}
+}
+
+// The BenchmarkReturnedUpTheStack* benchmarks model the common New(...)
+// then return-up-the-stack-and-log pattern: the stack is captured once,
+// when the error is created, and symbolized once, when it is logged.
+func BenchmarkReturnedUpTheStackEagerSymbolize(b *testing.B) {
+	SetLazySymbolize(false)
+
+	for i := 0; i < b.N; i++ {
+		e := New("benchmark error")
+		e.SetTrace(true)
+		_ = e.Error()
+	}
+}
+
+func BenchmarkReturnedUpTheStackLazySymbolize(b *testing.B) {
+	SetLazySymbolize(true)
+	defer SetLazySymbolize(false)
+
+	for i := 0; i < b.N; i++ {
+		e := New("benchmark error")
+		e.SetTrace(true)
+		_ = e.Error()
+	}
+}
@@ -172,6 +172,253 @@ func TestCode(t *testing.T) {
 	}
 }
 
+func TestUnwrap(t *testing.T) {
+	e := New("one")
+	e2 := New("two")
+	combined := e.Combine(e2)
+
+	unwrapped := combined.Unwrap()
+	if len(unwrapped) != 2 || unwrapped[0] != error(e) || unwrapped[1] != error(e2) {
+		t.Fatalf("Unwrap did not return the combined errors in order: %v", unwrapped)
+	}
+
+	if New("solo").Unwrap() != nil {
+		t.Fatal("Unwrap on an uncombined error should be nil")
+	}
+}
+
+func TestIsAndAs(t *testing.T) {
+	e := New("one")
+	sentinel := errors.New("sentinel")
+	combined := e.Combine(sentinel)
+
+	if !errors.Is(combined, e) {
+		t.Fatal("errors.Is did not find the original *Error in the combined chain")
+	}
+
+	if !errors.Is(combined, sentinel) {
+		t.Fatal("errors.Is did not find the sentinel in the combined chain")
+	}
+
+	if errors.Is(combined, errors.New("sentinel")) {
+		t.Fatal("errors.Is matched an unrelated error with the same message")
+	}
+
+	// combined is itself a *Error, so errors.As would assign it directly
+	// without ever calling its As method; call As directly to exercise
+	// "first assignable constituent" instead of errors.As's own
+	// direct-match short-circuit.
+	var target *Error
+	if !combined.As(&target) || target != e {
+		t.Fatalf("As did not assign the first *Error constituent: %v", target)
+	}
+}
+
+func TestIsDoesNotMatchUnrelatedErrorsByContent(t *testing.T) {
+	combined := New("resource missing").Combine(errors.New("cause"))
+	unrelated := New("resource missing")
+
+	if errors.Is(combined, unrelated) {
+		t.Fatal("errors.Is matched an unrelated *Error with the same Code and description")
+	}
+}
+
+func TestWrapAndWithMessage(t *testing.T) {
+	cause := errors.New("cause")
+
+	wrapped := Wrap(cause, "context")
+	if wrapped.Error() != "context: cause" {
+		t.Fatalf("Wrap produced unexpected message: %q", wrapped.Error())
+	}
+	if !errors.Is(wrapped, cause) {
+		t.Fatal("Wrap did not preserve the cause through Unwrap")
+	}
+
+	withMsg := WithMessage(cause, "context")
+	if withMsg.Error() != wrapped.Error() {
+		t.Fatalf("WithMessage diverged from Wrap: %q != %q", withMsg.Error(), wrapped.Error())
+	}
+}
+
+func TestWrapCapturesCallerFrame(t *testing.T) {
+	cause := errors.New("cause")
+
+	wrapped := Wrap(cause, "context")
+	if frames := wrapped.Frames(); len(frames) == 0 || !strings.Contains(frames[0].Func(), "TestWrapCapturesCallerFrame") {
+		t.Fatalf("Wrap captured the wrong frame: %v", frames)
+	}
+
+	withMsg := WithMessage(cause, "context")
+	if frames := withMsg.Frames(); len(frames) == 0 || !strings.Contains(frames[0].Func(), "TestWrapCapturesCallerFrame") {
+		t.Fatalf("WithMessage captured the wrong frame: %v", frames)
+	}
+}
+
+func TestMarshalJSONRoundTrip(t *testing.T) {
+	e := New("one").Combine(New("two"))
+	e.Code = 42
+
+	data, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	var out Error
+	if err := out.UnmarshalJSON(data); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+
+	if out.desc != e.desc || out.Code != e.Code {
+		t.Fatalf("round trip lost message or code: %+v vs %+v", out, e)
+	}
+
+	if len(out.errors) != len(e.errors) {
+		t.Fatalf("round trip lost causes: got %d, want %d", len(out.errors), len(e.errors))
+	}
+
+	if out.errors[0].Error() != "one" || out.errors[1].Error() != "two" {
+		t.Fatalf("round trip lost cause descriptions: %q, %q", out.errors[0].Error(), out.errors[1].Error())
+	}
+}
+
+func TestMarshalJSONIgnoresDebugAndTraceFlags(t *testing.T) {
+	e := New("one")
+
+	base, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	AlwaysDebug = true
+	AlwaysTrace = true
+	defer func() {
+		AlwaysDebug = false
+		AlwaysTrace = false
+	}()
+
+	withFlags, err := e.MarshalJSON()
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+
+	if string(base) != string(withFlags) {
+		t.Fatalf("AlwaysDebug/AlwaysTrace changed the JSON representation:\n%s\n%s", base, withFlags)
+	}
+}
+
+func TestMarshalLogfmt(t *testing.T) {
+	e := New("one").Combine(New("two"))
+	e.Code = 7
+
+	data, err := e.MarshalLogfmt()
+	if err != nil {
+		t.Fatalf("MarshalLogfmt failed: %v", err)
+	}
+
+	out := string(data)
+	for _, want := range []string{`message="one: two"`, "code=7", "causes.0.error=one", "causes.1.error=two"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("logfmt output missing %q: %s", want, out)
+		}
+	}
+}
+
+func TestRegisterCodeAndCodeOf(t *testing.T) {
+	const CodeNotFound Code = 404
+	RegisterCode(CodeNotFound, "NotFound", "the resource was not found", 404, 5)
+
+	info, ok := LookupCode(CodeNotFound)
+	if !ok || info.Name != "NotFound" || info.HTTPStatus != 404 || info.GRPCCode != 5 {
+		t.Fatalf("LookupCode returned unexpected metadata: %+v", info)
+	}
+
+	e := Errorf("missing").WithCode(CodeNotFound)
+	if e.Code != CodeNotFound {
+		t.Fatalf("WithCode did not set Code: %v", e.Code)
+	}
+
+	wrapped := New("request failed").Combine(e)
+	code, ok := CodeOf(wrapped)
+	if !ok || code != CodeNotFound {
+		t.Fatalf("CodeOf did not find the combined error's Code: %v %v", code, ok)
+	}
+
+	if _, ok := CodeOf(errors.New("plain")); ok {
+		t.Fatal("CodeOf found a Code on a plain error")
+	}
+}
+
+func TestFrames(t *testing.T) {
+	e := New("one")
+
+	frames := e.Frames()
+	if len(frames) == 0 {
+		t.Fatal("Frames returned no frames")
+	}
+
+	if !strings.Contains(frames[0].Func(), "TestFrames") {
+		t.Fatalf("top frame did not match call site: %s", frames[0].Func())
+	}
+
+	if frames[0].String() != fmt.Sprintf("%s %s %d", frames[0].Func(), frames[0].File(), frames[0].Line()) {
+		t.Fatalf("Frame.String() did not match its Func/File/Line: %q", frames[0].String())
+	}
+}
+
+func TestSetLazySymbolize(t *testing.T) {
+	SetLazySymbolize(true)
+	defer SetLazySymbolize(false)
+
+	e := New("lazy")
+	if !strings.Contains(e.Frames()[0].Func(), "TestSetLazySymbolize") {
+		t.Fatalf("lazily symbolized frame did not resolve correctly: %s", e.Frames()[0].Func())
+	}
+}
+
+func TestSetMaxStackDepth(t *testing.T) {
+	SetMaxStackDepth(1)
+	defer SetMaxStackDepth(32)
+
+	e := New("shallow")
+	if len(e.Frames()) != 1 {
+		t.Fatalf("SetMaxStackDepth(1) did not bound the captured stack: got %d frames", len(e.Frames()))
+	}
+}
+
+func TestFormatVerbs(t *testing.T) {
+	e := Errorf("boom")
+	e.Code = 7
+
+	if got := fmt.Sprintf("%s", e); got != "7 boom" {
+		t.Fatalf("%%s mismatch: got %q", got)
+	}
+
+	if got := fmt.Sprintf("%v", e); got != "7 boom" {
+		t.Fatalf("%%v mismatch: got %q", got)
+	}
+
+	if got := fmt.Sprintf("%q", e); got != `"7 boom"` {
+		t.Fatalf("%%q mismatch: got %q", got)
+	}
+
+	if got := fmt.Sprintf("%10s", e); got != "    7 boom" {
+		t.Fatalf("width was not honored: got %q", got)
+	}
+}
+
+func TestFormatPlusV(t *testing.T) {
+	e := Errorf("boom")
+
+	got := fmt.Sprintf("%+v", e)
+	if !strings.Contains(got, "boom") || !strings.Contains(got, "TestFormatPlusV") {
+		t.Fatalf("%%+v did not include the message and stack: %q", got)
+	}
+
+	if e.Error() == got {
+		t.Fatal("formatting with a plus-v verb should include trace even though SetTrace/AlwaysTrace were never set")
+	}
+}
+
 func TestErrorNew(t *testing.T) {
 	err := New("test")
 	if err.Error() != "test" {
@@ -0,0 +1,29 @@
+// Package erroredgrpc adapts errored.Code metadata onto gRPC status
+// errors. It is kept separate from the base errored package so that
+// importing errored does not pull in google.golang.org/grpc.
+package erroredgrpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/contiv/errored"
+)
+
+// GRPCStatus returns the *status.Status registered for err's Code via
+// errored.RegisterCode, using err.Error() as the status message. It
+// returns a codes.Unknown status when err has no Code, or when that
+// Code was never registered.
+func GRPCStatus(err error) *status.Status {
+	code, ok := errored.CodeOf(err)
+	if !ok {
+		return status.New(codes.Unknown, err.Error())
+	}
+
+	info, ok := errored.LookupCode(code)
+	if !ok {
+		return status.New(codes.Unknown, err.Error())
+	}
+
+	return status.New(codes.Code(info.GRPCCode), err.Error())
+}
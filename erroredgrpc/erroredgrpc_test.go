@@ -0,0 +1,25 @@
+package erroredgrpc
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/contiv/errored"
+)
+
+func TestGRPCStatus(t *testing.T) {
+	const codeNotFound errored.Code = 1002
+	errored.RegisterCode(codeNotFound, "NotFound", "the resource was not found", 404, int(codes.NotFound))
+
+	e := errored.Errorf("missing").WithCode(codeNotFound)
+	st := GRPCStatus(e)
+	if st.Code() != codes.NotFound {
+		t.Fatalf("GRPCStatus returned %v, want %v", st.Code(), codes.NotFound)
+	}
+
+	if st := GRPCStatus(errors.New("plain")); st.Code() != codes.Unknown {
+		t.Fatalf("GRPCStatus for an unregistered error returned %v, want %v", st.Code(), codes.Unknown)
+	}
+}
@@ -0,0 +1,27 @@
+// Package erroredhttp adapts errored.Code metadata onto HTTP status
+// codes. It is kept separate from the base errored package so that
+// importing errored does not pull in net/http.
+package erroredhttp
+
+import (
+	"net/http"
+
+	"github.com/contiv/errored"
+)
+
+// HTTPStatus returns the HTTP status registered for err's Code via
+// errored.RegisterCode. It returns http.StatusInternalServerError when
+// err has no Code, or when that Code was never registered.
+func HTTPStatus(err error) int {
+	code, ok := errored.CodeOf(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	info, ok := errored.LookupCode(code)
+	if !ok || info.HTTPStatus == 0 {
+		return http.StatusInternalServerError
+	}
+
+	return info.HTTPStatus
+}
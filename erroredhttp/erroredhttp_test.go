@@ -0,0 +1,23 @@
+package erroredhttp
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/contiv/errored"
+)
+
+func TestHTTPStatus(t *testing.T) {
+	const codeNotFound errored.Code = 1001
+	errored.RegisterCode(codeNotFound, "NotFound", "the resource was not found", http.StatusNotFound, 5)
+
+	e := errored.Errorf("missing").WithCode(codeNotFound)
+	if status := HTTPStatus(e); status != http.StatusNotFound {
+		t.Fatalf("HTTPStatus returned %d, want %d", status, http.StatusNotFound)
+	}
+
+	if status := HTTPStatus(errors.New("plain")); status != http.StatusInternalServerError {
+		t.Fatalf("HTTPStatus for an unregistered error returned %d, want %d", status, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,58 @@
+package errored
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Format implements fmt.Formatter, in the style popularized by
+// github.com/pkg/errors, so that callers can get rich diagnostics on
+// demand with log.Printf("%+v", err) instead of mutating the global
+// AlwaysDebug/AlwaysTrace flags or an error's own SetDebug/SetTrace
+// (which are racy to flip under concurrent use):
+//
+//	%s, %v  the concise message (Code prefix, combined-error joining)
+//	%q      the message, quoted
+//	%+v     the message followed by the full stack trace, regardless of
+//	        SetTrace/AlwaysTrace
+//
+// Width and precision, when given, apply to the message portion exactly
+// as they would formatting a plain string.
+func (e *Error) Format(s fmt.State, verb rune) {
+	msg := e.message()
+
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			formatMessage(s, 's', msg)
+			io.WriteString(s, "\n")
+			io.WriteString(s, e.traceString())
+			return
+		}
+		formatMessage(s, verb, msg)
+	case 's', 'q':
+		formatMessage(s, verb, msg)
+	}
+}
+
+// formatMessage re-applies the flags, width, and precision fmt already
+// parsed out of s onto msg, so %-10s, %.5v, and friends behave the same
+// for *Error as they do for a plain string.
+func formatMessage(s fmt.State, verb rune, msg string) {
+	format := "%"
+	for _, flag := range []byte{'-', '+', '#', ' ', '0'} {
+		if s.Flag(int(flag)) {
+			format += string(flag)
+		}
+	}
+	if width, ok := s.Width(); ok {
+		format += strconv.Itoa(width)
+	}
+	if prec, ok := s.Precision(); ok {
+		format += "." + strconv.Itoa(prec)
+	}
+	format += string(verb)
+
+	fmt.Fprintf(s, format, msg)
+}
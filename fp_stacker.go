@@ -0,0 +1,71 @@
+//go:build amd64 || arm64
+
+package errored
+
+import "unsafe"
+
+// getfp returns the calling function's frame pointer (BP on amd64, FP on
+// arm64). Implemented in fp_stacker_GOARCH.s.
+func getfp() unsafe.Pointer
+
+// FramePointerStacker is a Stacker that walks the saved frame-pointer
+// chain directly instead of going through runtime.Callers, avoiding the
+// cost of symbolizing frames that are never looked at. It only resolves
+// function/file/line for a frame when Func, File, Line, or String is
+// called on it (see SetLazySymbolize, which has no effect on this
+// Stacker since it is always lazy).
+//
+// It relies on the Go compiler's frame-pointer chain, which is only
+// maintained on amd64 and arm64, so it is only available on those
+// GOARCHes. Unlike runtime.Callers, it does not expand inlined frames:
+// each saved return address yields exactly one Frame, even if the
+// compiler inlined several calls into it.
+type FramePointerStacker struct{}
+
+// frameAdjust accounts for the one frame of skew between this Stacker's
+// BP walk and runtime.Callers' skip convention: bp starts at getfp's
+// caller (Capture itself, since getfp has no frame of its own to walk
+// through), so pcs[0] is the return address into Capture's caller. When
+// Capture is called through the Stacker interface, as makeStack does,
+// that caller is the compiler-generated (*FramePointerStacker).Capture
+// wrapper, which runtime.Callers leaves out; pcs[1] is then the frame
+// that index 2 of a runtime.Callers call inside Capture reports, so
+// pcs[skip-1] matches runtime.Callers(skip). A direct call to Capture,
+// with no wrapper frame in between, is off by one more.
+const frameAdjust = 1
+
+// Capture walks the frame-pointer chain starting at its caller, skipping
+// skip frames in the same sense as runtime.Callers, and returns one
+// unresolved Frame per saved return address.
+func (FramePointerStacker) Capture(skip int) []*Frame {
+	var pcs []uintptr
+
+	bp := getfp()
+	for i := 0; i < maxStackDepth && bp != nil; i++ {
+		retAddr := *(*uintptr)(unsafe.Pointer(uintptr(bp) + unsafe.Sizeof(bp)))
+		if retAddr == 0 {
+			break
+		}
+		pcs = append(pcs, retAddr)
+
+		savedBP := *(*unsafe.Pointer)(bp)
+		if uintptr(savedBP) <= uintptr(bp) {
+			break
+		}
+		bp = savedBP
+	}
+
+	skip -= frameAdjust
+	switch {
+	case skip >= len(pcs):
+		pcs = nil
+	case skip > 0:
+		pcs = pcs[skip:]
+	}
+
+	frames := make([]*Frame, len(pcs))
+	for i, pc := range pcs {
+		frames[i] = newUnresolvedFrame(pc)
+	}
+	return frames
+}
@@ -0,0 +1,24 @@
+//go:build amd64 || arm64
+
+package errored
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFramePointerStacker(t *testing.T) {
+	prev := currentStacker
+	SetStacker(FramePointerStacker{})
+	defer SetStacker(prev)
+
+	e := New("fp test")
+	frames := e.Frames()
+	if len(frames) == 0 {
+		t.Fatal("FramePointerStacker captured no frames")
+	}
+
+	if !strings.Contains(frames[0].Func(), "TestFramePointerStacker") {
+		t.Fatalf("top frame did not match call site: %s", frames[0].Func())
+	}
+}
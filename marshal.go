@@ -0,0 +1,141 @@
+package errored
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonFrame is the wire representation of a single stack frame.
+type jsonFrame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// jsonCause is the wire representation of one of the errors combined
+// into an *Error via Combine.
+type jsonCause struct {
+	Error string      `json:"error"`
+	Stack []jsonFrame `json:"stack"`
+}
+
+// jsonError is the wire representation of an *Error, used by both
+// MarshalJSON and UnmarshalJSON.
+type jsonError struct {
+	Message string      `json:"message"`
+	Code    Code        `json:"code"`
+	Stack   []jsonFrame `json:"stack"`
+	Causes  []jsonCause `json:"causes,omitempty"`
+}
+
+func framesToJSON(frames []*Frame) []jsonFrame {
+	out := make([]jsonFrame, len(frames))
+	for i, f := range frames {
+		out[i] = jsonFrame{Func: f.Func(), File: f.File(), Line: f.Line()}
+	}
+	return out
+}
+
+func framesFromJSON(frames []jsonFrame) []*Frame {
+	out := make([]*Frame, len(frames))
+	for i, f := range frames {
+		out[i] = newResolvedFrame(f.Func, f.File, f.Line)
+	}
+	return out
+}
+
+// MarshalJSON renders e as message, code, stack, and causes fields, so
+// structured loggers and RPC error payloads can carry the full detail of
+// an *Error instead of just its Error() string. The output always
+// includes the full stack and causes regardless of SetDebug/SetTrace or
+// AlwaysDebug/AlwaysTrace.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	je := jsonError{
+		Message: e.desc,
+		Code:    e.Code,
+		Stack:   framesToJSON(e.stack),
+	}
+
+	for i, err := range e.errors {
+		cause := jsonCause{Error: descOf(err)}
+		if i < len(e.stack) {
+			cause.Stack = framesToJSON(e.stack[i : i+1])
+		}
+		je.Causes = append(je.Causes, cause)
+	}
+
+	return json.Marshal(je)
+}
+
+// UnmarshalJSON populates e from the payload produced by MarshalJSON.
+// Combined causes are reconstructed as shallow *Error values carrying
+// only their description and originating frame.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var je jsonError
+	if err := json.Unmarshal(data, &je); err != nil {
+		return err
+	}
+
+	e.desc = je.Message
+	e.Code = je.Code
+	e.stack = framesFromJSON(je.Stack)
+
+	e.errors = nil
+	for _, c := range je.Causes {
+		e.errors = append(e.errors, &Error{
+			desc:  c.Error,
+			stack: framesFromJSON(c.Stack),
+		})
+	}
+
+	return nil
+}
+
+// MarshalLogfmt renders e as a single line of logfmt-style key=value
+// pairs: message, code (when non-zero), one stack.N.{func,file,line}
+// triple per frame,
+// and one causes.N.error (plus its originating frame) per error
+// combined into e. Like MarshalJSON, it always renders full detail.
+func (e *Error) MarshalLogfmt() ([]byte, error) {
+	var sb strings.Builder
+
+	writeLogfmtKV(&sb, "message", e.desc)
+	if e.Code != 0 {
+		writeLogfmtKV(&sb, "code", e.Code)
+	}
+
+	for i, f := range e.stack {
+		writeLogfmtKV(&sb, fmt.Sprintf("stack.%d.func", i), f.Func())
+		writeLogfmtKV(&sb, fmt.Sprintf("stack.%d.file", i), f.File())
+		writeLogfmtKV(&sb, fmt.Sprintf("stack.%d.line", i), f.Line())
+	}
+
+	for i, err := range e.errors {
+		writeLogfmtKV(&sb, fmt.Sprintf("causes.%d.error", i), descOf(err))
+		if i < len(e.stack) {
+			f := e.stack[i]
+			writeLogfmtKV(&sb, fmt.Sprintf("causes.%d.stack.0.func", i), f.Func())
+			writeLogfmtKV(&sb, fmt.Sprintf("causes.%d.stack.0.file", i), f.File())
+			writeLogfmtKV(&sb, fmt.Sprintf("causes.%d.stack.0.line", i), f.Line())
+		}
+	}
+
+	return []byte(strings.TrimSuffix(sb.String(), " ")), nil
+}
+
+func writeLogfmtKV(sb *strings.Builder, key string, value interface{}) {
+	fmt.Fprintf(sb, "%s=%s ", key, logfmtValue(value))
+}
+
+func logfmtValue(v interface{}) string {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Sprintf("%v", v)
+	}
+	if strings.ContainsAny(s, " \"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+package errored
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// Frame describes a single entry in an Error's stack trace. Its
+// function, file, and line are available through Func, File, and Line.
+// Depending on the active Stacker and SetLazySymbolize, a Frame may
+// carry only a raw program counter until one of those is first
+// accessed.
+type Frame struct {
+	pc   uintptr
+	once sync.Once
+
+	funcName string
+	fileName string
+	lineNo   int
+}
+
+func newResolvedFrame(funcName, fileName string, lineNo int) *Frame {
+	f := &Frame{}
+	f.once.Do(func() {
+		f.funcName = funcName
+		f.fileName = fileName
+		f.lineNo = lineNo
+	})
+	return f
+}
+
+func newUnresolvedFrame(pc uintptr) *Frame {
+	return &Frame{pc: pc}
+}
+
+func (f *Frame) symbolize() {
+	f.once.Do(func() {
+		// f.pc is a return address, i.e. the instruction after the call;
+		// resolve pc-1, the call itself, so a call in tail position
+		// (whose return address can be the next function's entry point)
+		// still resolves to the correct frame.
+		pc := f.pc - 1
+
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			return
+		}
+
+		file, line := fn.FileLine(pc)
+		f.funcName = fn.Name()
+		f.fileName = filepath.Base(file)
+		f.lineNo = line
+	})
+}
+
+// Func returns the fully-qualified function name this frame was
+// captured in, resolving it from the captured program counter if it has
+// not been resolved yet.
+func (f *Frame) Func() string {
+	f.symbolize()
+	return f.funcName
+}
+
+// File returns the base name of the source file this frame was captured
+// in, resolving it from the captured program counter if it has not been
+// resolved yet.
+func (f *Frame) File() string {
+	f.symbolize()
+	return f.fileName
+}
+
+// Line returns the source line this frame was captured at, resolving it
+// from the captured program counter if it has not been resolved yet.
+func (f *Frame) Line() int {
+	f.symbolize()
+	return f.lineNo
+}
+
+// String renders a frame the way it appears in debug and trace output.
+func (f *Frame) String() string {
+	return fmt.Sprintf("%s %s %d", f.Func(), f.File(), f.Line())
+}
+
+// Stacker captures the program counters of the calling goroutine's
+// stack, skipping skip frames in the same sense as runtime.Callers.
+// Implementations may defer symbolizing those program counters into
+// function/file/line information until a Frame's Func, File, or Line is
+// first accessed; see SetLazySymbolize.
+type Stacker interface {
+	Capture(skip int) []*Frame
+}
+
+// currentStacker is used by New, Errorf, and Combine to capture stack
+// traces; change it with SetStacker.
+var currentStacker Stacker = runtimeStacker{}
+
+// maxStackDepth bounds how many frames are captured per error; change it
+// with SetMaxStackDepth.
+var maxStackDepth = 32
+
+// lazySymbolize controls whether runtimeStacker resolves captured
+// frames eagerly or defers to first access; change it with
+// SetLazySymbolize.
+var lazySymbolize = false
+
+// SetStacker replaces the package-wide Stacker used to capture stack
+// traces. The default is a runtime.Callers+CallersFrames walker;
+// FramePointerStacker is a faster alternative on GOARCHes that support
+// it.
+func SetStacker(s Stacker) {
+	currentStacker = s
+}
+
+// SetMaxStackDepth bounds how many frames are captured per error. The
+// default is 32.
+func SetMaxStackDepth(depth int) {
+	maxStackDepth = depth
+}
+
+// SetLazySymbolize controls whether the default Stacker resolves a
+// captured frame's function/file/line eagerly, at capture time (the
+// default), or defers resolution until the frame is first accessed via
+// Func, File, Line, or String. Deferring trades a slower first access
+// for a cheaper New/Errorf/Combine on the hot path.
+func SetLazySymbolize(lazy bool) {
+	lazySymbolize = lazy
+}
+
+// runtimeStacker is the default Stacker, built on runtime.Callers and
+// runtime.CallersFrames.
+type runtimeStacker struct{}
+
+func (runtimeStacker) Capture(skip int) []*Frame {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pcs)
+	pcs = pcs[:n]
+
+	if lazySymbolize {
+		out := make([]*Frame, len(pcs))
+		for i, pc := range pcs {
+			out[i] = newUnresolvedFrame(pc)
+		}
+		return out
+	}
+
+	var out []*Frame
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		out = append(out, newResolvedFrame(f.Function, filepath.Base(f.File), f.Line))
+		if !more {
+			break
+		}
+	}
+	return out
+}